@@ -3,10 +3,14 @@ package rescuetime
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"math/rand"
+	"net"
 	"net/http"
 	"net/url"
 	"reflect"
@@ -18,13 +22,230 @@ import (
 )
 
 const (
-	analyticDataURL string = "https://www.rescuetime.com/anapi/data"
-	dailySummaryURL string = "https://www.rescuetime.com/anapi/daily_summary_feed"
+	defaultBaseURL          string = "https://www.rescuetime.com"
+	analyticDataURL         string = "/anapi/data"
+	dailySummaryURL         string = "/anapi/daily_summary_feed"
+	alertsFeedURL           string = "/anapi/alerts_feed"
+	highlightsFeedURL       string = "/anapi/highlights_feed"
+	highlightsPostURL       string = "/anapi/highlights_post"
+	startFocusTimeURL       string = "/anapi/start_focustime"
+	endFocusTimeURL         string = "/anapi/end_focustime"
+	focusTimeStartedFeedURL string = "/anapi/focustime_started_feed"
+	focusTimeEndedFeedURL   string = "/anapi/focustime_ended_feed"
+	offlineTimePostURL      string = "/anapi/offline_time_post"
 )
 
-// RescueTime contains the user's API key
+// RescueTime is a client for the RescueTime API.
 type RescueTime struct {
+	// APIKey is the user's RescueTime API key.
 	APIKey string
+
+	httpClient  *http.Client
+	baseURL     string
+	retryPolicy RetryPolicy
+	rateLimiter RateLimiter
+}
+
+// ClientOption configures a RescueTime client constructed by NewClient.
+type ClientOption func(*RescueTime)
+
+// WithHTTPClient overrides the http.Client used to make API requests,
+// allowing callers to supply a custom transport, timeout, or cookie jar.
+func WithHTTPClient(client *http.Client) ClientOption {
+	return func(r *RescueTime) {
+		r.httpClient = client
+	}
+}
+
+// WithBaseURL overrides the RescueTime API base URL. It is primarily useful
+// for pointing the client at a mock server in tests.
+func WithBaseURL(baseURL string) ClientOption {
+	return func(r *RescueTime) {
+		r.baseURL = baseURL
+	}
+}
+
+// WithRetryPolicy overrides the policy used to decide whether and how long
+// to wait before retrying a failed request. The default is ExponentialBackoff{}.
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(r *RescueTime) {
+		r.retryPolicy = policy
+	}
+}
+
+// WithRateLimiter attaches a RateLimiter that every request waits on before
+// being sent, allowing a limiter to be shared across many RescueTime clients
+// or goroutines.
+func WithRateLimiter(limiter RateLimiter) ClientOption {
+	return func(r *RescueTime) {
+		r.rateLimiter = limiter
+	}
+}
+
+// NewClient returns a RescueTime client for the given API key, configured
+// with any supplied options.
+func NewClient(apiKey string, opts ...ClientOption) *RescueTime {
+	r := &RescueTime{
+		APIKey:     apiKey,
+		httpClient: http.DefaultClient,
+		baseURL:    defaultBaseURL,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// client returns the http.Client to use for requests, falling back to
+// http.DefaultClient for a RescueTime value constructed without NewClient.
+func (r *RescueTime) client() *http.Client {
+	if r.httpClient != nil {
+		return r.httpClient
+	}
+	return http.DefaultClient
+}
+
+// base returns the API base URL to use for requests, falling back to
+// defaultBaseURL for a RescueTime value constructed without NewClient.
+func (r *RescueTime) base() string {
+	if r.baseURL != "" {
+		return r.baseURL
+	}
+	return defaultBaseURL
+}
+
+// policy returns the RetryPolicy to use for requests, falling back to
+// ExponentialBackoff{} for a RescueTime value constructed without NewClient.
+func (r *RescueTime) policy() RetryPolicy {
+	if r.retryPolicy != nil {
+		return r.retryPolicy
+	}
+	return ExponentialBackoff{}
+}
+
+// APIError is returned when the RescueTime API responds with a non-2xx
+// status that is not retried: the configured RetryPolicy declined to retry
+// a GET, or the request was a POST, which is never retried.
+type APIError struct {
+	StatusCode int
+	Body       []byte
+	// RetryAfter is the delay requested by the response's Retry-After
+	// header, or zero if none was present.
+	RetryAfter time.Duration
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("rescuetime: request failed with status %d: %s", e.StatusCode, e.Body)
+}
+
+// RateLimiter restricts the rate of outgoing API requests. It is satisfied
+// by *golang.org/x/time/rate.Limiter, letting callers share one limiter
+// across many RescueTime clients or goroutines.
+type RateLimiter interface {
+	Wait(ctx context.Context) error
+}
+
+// RetryPolicy decides whether a failed request should be retried and how
+// long to wait before the next attempt. resp is nil when the request failed
+// before a response was received (e.g. a network timeout).
+type RetryPolicy interface {
+	Retry(attempt int, resp *http.Response, err error) (retry bool, delay time.Duration)
+}
+
+// ExponentialBackoff is the default RetryPolicy. It retries 429 and
+// transient 5xx responses plus timeout errors, backing off exponentially
+// with jitter, and honors the Retry-After header when present. The zero
+// value makes up to 5 attempts in all (4 retries) with sensible delay bounds.
+type ExponentialBackoff struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+func (b ExponentialBackoff) maxAttempts() int {
+	if b.MaxAttempts > 0 {
+		return b.MaxAttempts
+	}
+	return 5
+}
+
+func (b ExponentialBackoff) baseDelay() time.Duration {
+	if b.BaseDelay > 0 {
+		return b.BaseDelay
+	}
+	return 250 * time.Millisecond
+}
+
+func (b ExponentialBackoff) maxDelay() time.Duration {
+	if b.MaxDelay > 0 {
+		return b.MaxDelay
+	}
+	return 30 * time.Second
+}
+
+// Retry implements RetryPolicy.
+func (b ExponentialBackoff) Retry(attempt int, resp *http.Response, err error) (bool, time.Duration) {
+	if attempt+1 >= b.maxAttempts() {
+		return false, 0
+	}
+
+	if err != nil {
+		var netErr net.Error
+		if !errors.As(err, &netErr) || !netErr.Timeout() {
+			return false, 0
+		}
+		return true, b.backoff(attempt)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		if d, ok := retryAfter(resp); ok {
+			return true, d
+		}
+		return true, b.backoff(attempt)
+	default:
+		return false, 0
+	}
+}
+
+func (b ExponentialBackoff) backoff(attempt int) time.Duration {
+	delay := b.baseDelay() << uint(attempt)
+	if delay <= 0 || delay > b.maxDelay() {
+		delay = b.maxDelay()
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay)/2+1))
+}
+
+// retryAfter parses a response's Retry-After header, which may be either a
+// number of seconds or an HTTP date.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	h := resp.Header.Get("Retry-After")
+	if h == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(h); err == nil {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(h); err == nil {
+		return time.Until(t), true
+	}
+	return 0, false
+}
+
+// sleep blocks for d, or until ctx is done, whichever comes first.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
 }
 
 // DailySummary is a users summary for a single day
@@ -90,28 +311,156 @@ type DailySummary struct {
 	VeryProductivePercentage                    float64 `json:"very_productive_percentage"`
 }
 
-// AnalyticDataQueryParameters is used to provide parameters to the Analytic Data API
+// Perspective selects how the Analytic Data API groups its results.
+type Perspective string
+
+// Supported Perspective values.
+const (
+	PerspectiveRank     Perspective = "rank"
+	PerspectiveInterval Perspective = "interval"
+)
+
+func (p Perspective) validate() error {
+	switch p {
+	case PerspectiveRank, PerspectiveInterval:
+		return nil
+	default:
+		return fmt.Errorf("rescuetime: invalid perspective %q", p)
+	}
+}
+
+// ResolutionTime selects the time bucketing used by the Analytic Data API
+// when Perspective is PerspectiveInterval.
+type ResolutionTime string
+
+// Supported ResolutionTime values.
+const (
+	ResolutionMinute ResolutionTime = "minute"
+	ResolutionHour   ResolutionTime = "hour"
+	ResolutionDay    ResolutionTime = "day"
+	ResolutionWeek   ResolutionTime = "week"
+	ResolutionMonth  ResolutionTime = "month"
+)
+
+func (rt ResolutionTime) validate() error {
+	switch rt {
+	case ResolutionMinute, ResolutionHour, ResolutionDay, ResolutionWeek, ResolutionMonth:
+		return nil
+	default:
+		return fmt.Errorf("rescuetime: invalid resolution time %q", rt)
+	}
+}
+
+// RestrictKind selects what RestrictThing/RestrictThingy restrict the
+// Analytic Data API query to.
+type RestrictKind string
+
+// Supported RestrictKind values.
+const (
+	KindCategory     RestrictKind = "category"
+	KindActivity     RestrictKind = "activity"
+	KindProductivity RestrictKind = "productivity"
+	KindDocument     RestrictKind = "document"
+	KindEfficiency   RestrictKind = "efficiency"
+)
+
+func (k RestrictKind) validate() error {
+	switch k {
+	case KindCategory, KindActivity, KindProductivity, KindDocument, KindEfficiency:
+		return nil
+	default:
+		return fmt.Errorf("rescuetime: invalid restrict kind %q", k)
+	}
+}
+
+// Period is a date range, used both to restrict an Analytic Data query and,
+// via IterateAnalyticData, to describe a span that may need to be split
+// across several requests.
+type Period struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Validate reports an error if the period is missing a bound or if End is
+// not after Start.
+func (p Period) Validate() error {
+	if p.Start.IsZero() || p.End.IsZero() {
+		return errors.New("rescuetime: period start and end must both be set")
+	}
+	if !p.End.After(p.Start) {
+		return errors.New("rescuetime: period end must be after start")
+	}
+	return nil
+}
+
+// AnalyticDataQueryParameters is used to provide parameters to the Analytic
+// Data API. Only fields that have been explicitly set are sent with the
+// request; the zero value of a field is treated as unset.
 type AnalyticDataQueryParameters struct {
-	Perspective    string `field_name:"perspective"`
-	ResolutionTime string `field_name:"resolution_time"`
-	RestrictGroup  string `field_name:"restrict_group"`
-	RestrictBegin  string `field_name:"restrict_begin"`
-	RestrictEnd    string `field_name:"restrict_end"`
-	RestrictKind   string `field_name:"restrict_kind"`
-	RestrictThing  string `field_name:"restrict_thing"`
-	RestrictThingy string `field_name:"restrict_thingy"`
+	Perspective    Perspective
+	ResolutionTime ResolutionTime
+	RestrictGroup  string
+	RestrictBegin  time.Time
+	RestrictEnd    time.Time
+	RestrictKind   RestrictKind
+	RestrictThing  string
+	RestrictThingy string
+}
+
+// dateFormat is the YYYY-MM-DD date format expected by the Analytic Data API.
+const dateFormat = "2006-01-02"
+
+// encode serializes the parameters that have been set into URL query values,
+// returning an error if an enum field holds an unrecognized value.
+func (p *AnalyticDataQueryParameters) encode() (url.Values, error) {
+	values := url.Values{}
+
+	if p.Perspective != "" {
+		if err := p.Perspective.validate(); err != nil {
+			return nil, err
+		}
+		values.Set("perspective", string(p.Perspective))
+	}
+	if p.ResolutionTime != "" {
+		if err := p.ResolutionTime.validate(); err != nil {
+			return nil, err
+		}
+		values.Set("resolution_time", string(p.ResolutionTime))
+	}
+	if p.RestrictGroup != "" {
+		values.Set("restrict_group", p.RestrictGroup)
+	}
+	if !p.RestrictBegin.IsZero() {
+		values.Set("restrict_begin", p.RestrictBegin.Format(dateFormat))
+	}
+	if !p.RestrictEnd.IsZero() {
+		values.Set("restrict_end", p.RestrictEnd.Format(dateFormat))
+	}
+	if p.RestrictKind != "" {
+		if err := p.RestrictKind.validate(); err != nil {
+			return nil, err
+		}
+		values.Set("restrict_kind", string(p.RestrictKind))
+	}
+	if p.RestrictThing != "" {
+		values.Set("restrict_thing", p.RestrictThing)
+	}
+	if p.RestrictThingy != "" {
+		values.Set("restrict_thingy", p.RestrictThingy)
+	}
+	return values, nil
 }
 
 // AnalyticData describes an Analytic Data API result
 type AnalyticData struct {
 	Notes      string                       `json:"notes"`
 	RowHeaders []string                     `json:"row_headers"`
-	Rows       []row                        `json:"rows"`
+	Rows       []Row                        `json:"rows"`
 	Parameters *AnalyticDataQueryParameters `json:"-,omitempty"`
 }
 
 // Row is a single row in an Analytic Data API result
-type row struct {
+type Row struct {
 	Date             time.Time
 	Rank             int
 	TimeSpentSeconds int
@@ -122,38 +471,8 @@ type row struct {
 	Productivity     int
 }
 
-func structToMap(i interface{}) (values url.Values) {
-	values = url.Values{}
-	iVal := reflect.ValueOf(i).Elem()
-	typ := iVal.Type()
-	for i := 0; i < iVal.NumField(); i++ {
-		f := iVal.Field(i)
-		// Convert each type into a string for the url.Values string map
-		var v string
-		switch f.Interface().(type) {
-		case int, int8, int16, int32, int64:
-			v = strconv.FormatInt(f.Int(), 10)
-		case uint, uint8, uint16, uint32, uint64:
-			v = strconv.FormatUint(f.Uint(), 10)
-		case float32:
-			v = strconv.FormatFloat(f.Float(), 'f', 4, 32)
-		case float64:
-			v = strconv.FormatFloat(f.Float(), 'f', 4, 64)
-		case []byte:
-			v = string(f.Bytes())
-		case string:
-			v = f.String()
-		}
-		if v == "" {
-			continue
-		}
-		values.Set(typ.Field(i).Tag.Get("field_name"), v)
-	}
-	return
-}
-
-func (r *RescueTime) buildURL(baseURL string, urlValues url.Values) (string, error) {
-	parsedURL, err := url.Parse(baseURL)
+func (r *RescueTime) buildURL(path string, urlValues url.Values) (string, error) {
+	parsedURL, err := url.Parse(r.base() + path)
 	if err != nil {
 		return "", err
 	}
@@ -174,35 +493,118 @@ func titleCase(src string) string {
 	return string(bytes.Join(chunks, nil))
 }
 
-func (r *RescueTime) getResponse(getURL string) ([]byte, error) {
+// do issues an HTTP request against the given RescueTime API path and
+// returns the raw response body. If body is non-nil, it is sent as a
+// form-encoded POST body, per the RescueTime API; otherwise a GET is issued.
+// A non-2xx response is returned as an *APIError.
+//
+// The client's RetryPolicy only applies to GET requests. RescueTime's POST
+// actions (PostHighlight, PostOfflineTime, StartFocusTime, EndFocusTime) are
+// not idempotent, so retrying one after a timeout or 5xx risks double-
+// submitting an action whose first attempt actually succeeded; do never
+// retries those, regardless of the configured policy.
+func (r *RescueTime) do(ctx context.Context, path string, query, body url.Values) ([]byte, error) {
 	if r.APIKey == "" {
 		return nil, errors.New("Please provide API key")
 	}
-	response, err := http.Get(getURL)
+	if query == nil {
+		query = url.Values{}
+	}
+	builtURL, err := r.buildURL(path, query)
 	if err != nil {
 		return nil, err
 	}
-	defer response.Body.Close()
-	contents, err := ioutil.ReadAll(response.Body)
+
+	method := http.MethodGet
+	var bodyBytes []byte
+	if body != nil {
+		method = http.MethodPost
+		bodyBytes = []byte(body.Encode())
+	}
+
+	policy := r.policy()
+	for attempt := 0; ; attempt++ {
+		if r.rateLimiter != nil {
+			if err := r.rateLimiter.Wait(ctx); err != nil {
+				return nil, err
+			}
+		}
+
+		var bodyReader io.Reader
+		if bodyBytes != nil {
+			bodyReader = bytes.NewReader(bodyBytes)
+		}
+		req, err := http.NewRequestWithContext(ctx, method, builtURL, bodyReader)
+		if err != nil {
+			return nil, err
+		}
+		if bodyReader != nil {
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+
+		response, err := r.client().Do(req)
+		if err != nil {
+			if method == http.MethodGet {
+				if retry, delay := policy.Retry(attempt, nil, err); retry {
+					if sleepErr := sleep(ctx, delay); sleepErr != nil {
+						return nil, sleepErr
+					}
+					continue
+				}
+			}
+			return nil, err
+		}
+
+		contents, readErr := ioutil.ReadAll(response.Body)
+		response.Body.Close()
+		if readErr != nil {
+			return nil, readErr
+		}
+
+		if response.StatusCode < 200 || response.StatusCode >= 300 {
+			apiErr := &APIError{StatusCode: response.StatusCode, Body: contents}
+			if d, ok := retryAfter(response); ok {
+				apiErr.RetryAfter = d
+			}
+			if method == http.MethodGet {
+				if retry, delay := policy.Retry(attempt, response, nil); retry {
+					if apiErr.RetryAfter > delay {
+						delay = apiErr.RetryAfter
+					}
+					if sleepErr := sleep(ctx, delay); sleepErr != nil {
+						return nil, sleepErr
+					}
+					continue
+				}
+			}
+			return nil, apiErr
+		}
+
+		return contents, nil
+	}
+}
+
+// doJSON is do, followed by unmarshaling the response body into out.
+func (r *RescueTime) doJSON(ctx context.Context, path string, query, body url.Values, out interface{}) error {
+	contents, err := r.do(ctx, path, query, body)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	return contents, nil
+	return json.Unmarshal(contents, out)
 }
 
-// GetAnalyticData makes a request to the Analytic Data API with the provided parameters.
+// GetAnalyticDataWithContext makes a request to the Analytic Data API with the provided parameters.
 // If a timezone is given, all dates will be located in the given timezone, otherwise system's local timezone.
-func (r *RescueTime) GetAnalyticData(timezone string, parameters *AnalyticDataQueryParameters) (AnalyticData, error) {
+// The request is bound to ctx, so callers can time it out or cancel it.
+func (r *RescueTime) GetAnalyticDataWithContext(ctx context.Context, timezone string, parameters *AnalyticDataQueryParameters) (AnalyticData, error) {
 	var rtd AnalyticData
 
-	params := structToMap(parameters)
-
-	builtURL, err := r.buildURL(analyticDataURL, params)
+	params, err := parameters.encode()
 	if err != nil {
 		return rtd, err
 	}
 
-	contents, err := r.getResponse(builtURL)
+	contents, err := r.do(ctx, analyticDataURL, params, nil)
 	if err != nil {
 		return rtd, err
 	}
@@ -230,7 +632,7 @@ func (r *RescueTime) GetAnalyticData(timezone string, parameters *AnalyticDataQu
 
 	var toAppend []Row
 	for _, entry := range currentJSON.Get("rows").MustArray() {
-		var aRow row
+		var aRow Row
 		for index, column := range entry.([]interface{}) {
 			thisHeader := headersMap[index]
 			field := reflect.ValueOf(&aRow).Elem().FieldByName(thisHeader)
@@ -264,21 +666,425 @@ func (r *RescueTime) GetAnalyticData(timezone string, parameters *AnalyticDataQu
 	return data, nil
 }
 
+// GetAnalyticData makes a request to the Analytic Data API with the provided parameters.
+// If a timezone is given, all dates will be located in the given timezone, otherwise system's local timezone.
+func (r *RescueTime) GetAnalyticData(timezone string, parameters *AnalyticDataQueryParameters) (AnalyticData, error) {
+	return r.GetAnalyticDataWithContext(context.Background(), timezone, parameters)
+}
+
+// GetDailySummaryWithContext returns the daily summary for the user. The
+// request is bound to ctx, so callers can time it out or cancel it.
+func (r *RescueTime) GetDailySummaryWithContext(ctx context.Context) ([]DailySummary, error) {
+	var summaries []DailySummary
+	if err := r.doJSON(ctx, dailySummaryURL, nil, nil, &summaries); err != nil {
+		return summaries, err
+	}
+	return summaries, nil
+}
+
 // GetDailySummary returns the daily summary for the user.
 func (r *RescueTime) GetDailySummary() ([]DailySummary, error) {
+	return r.GetDailySummaryWithContext(context.Background())
+}
+
+// GetDailySummaryAsOfWithContext returns the daily summary feed restricted
+// to the given date, rather than the default trailing window.
+func (r *RescueTime) GetDailySummaryAsOfWithContext(ctx context.Context, date time.Time) ([]DailySummary, error) {
 	var summaries []DailySummary
-	builtURL, err := r.buildURL(dailySummaryURL, url.Values{})
-	if err != nil {
+	query := url.Values{"restrict_begin": {date.Format(dateFormat)}}
+	if err := r.doJSON(ctx, dailySummaryURL, query, nil, &summaries); err != nil {
 		return summaries, err
 	}
-	contents, err := r.getResponse(builtURL)
-	if err != nil {
-		return summaries, err
+	return summaries, nil
+}
+
+// GetDailySummaryAsOf returns the daily summary feed restricted to the given date.
+func (r *RescueTime) GetDailySummaryAsOf(date time.Time) ([]DailySummary, error) {
+	return r.GetDailySummaryAsOfWithContext(context.Background(), date)
+}
+
+// Alert is a single entry in the Alerts Feed API.
+type Alert struct {
+	AlertID     int    `json:"alert_id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Type        string `json:"type"`
+}
+
+// GetAlertsFeedWithContext returns the user's configured alerts. The request
+// is bound to ctx, so callers can time it out or cancel it.
+func (r *RescueTime) GetAlertsFeedWithContext(ctx context.Context) ([]Alert, error) {
+	var alerts []Alert
+	if err := r.doJSON(ctx, alertsFeedURL, nil, nil, &alerts); err != nil {
+		return alerts, err
+	}
+	return alerts, nil
+}
+
+// GetAlertsFeed returns the user's configured alerts.
+func (r *RescueTime) GetAlertsFeed() ([]Alert, error) {
+	return r.GetAlertsFeedWithContext(context.Background())
+}
+
+// Highlight is a single entry in the Highlights Feed API.
+type Highlight struct {
+	Date        string `json:"date"`
+	Description string `json:"description"`
+	Source      string `json:"source"`
+}
+
+// GetHighlightsFeedWithContext returns the user's recorded Highlights. The
+// request is bound to ctx, so callers can time it out or cancel it.
+func (r *RescueTime) GetHighlightsFeedWithContext(ctx context.Context) ([]Highlight, error) {
+	var highlights []Highlight
+	if err := r.doJSON(ctx, highlightsFeedURL, nil, nil, &highlights); err != nil {
+		return highlights, err
+	}
+	return highlights, nil
+}
+
+// GetHighlightsFeed returns the user's recorded Highlights.
+func (r *RescueTime) GetHighlightsFeed() ([]Highlight, error) {
+	return r.GetHighlightsFeedWithContext(context.Background())
+}
+
+// PostHighlightRequest is the payload accepted by the Highlights Post API.
+type PostHighlightRequest struct {
+	// Description is the Highlight text. Required.
+	Description string
+	// Date is the day the Highlight is recorded against. If zero, the
+	// RescueTime API records it against the current day.
+	Date time.Time
+	// Source optionally attributes the Highlight to an integration name.
+	Source string
+}
+
+func (p *PostHighlightRequest) encode() url.Values {
+	values := url.Values{"description": {p.Description}}
+	if !p.Date.IsZero() {
+		values.Set("highlight_date", p.Date.Format(dateFormat))
+	}
+	if p.Source != "" {
+		values.Set("source", p.Source)
+	}
+	return values
+}
+
+// postResponse is the shape of the confirmation body returned by the
+// RescueTime action endpoints (Highlights Post, FocusTime, Offline Time).
+type postResponse struct {
+	Successful bool `json:"successful"`
+}
+
+// doPostAction issues a POST to path and decodes a postResponse, returning
+// an error if the transport or JSON decoding failed, or if the API itself
+// reported the action as unsuccessful.
+func (r *RescueTime) doPostAction(ctx context.Context, path string, body url.Values) error {
+	var resp postResponse
+	if err := r.doJSON(ctx, path, nil, body, &resp); err != nil {
+		return err
+	}
+	if !resp.Successful {
+		return fmt.Errorf("rescuetime: %s reported an unsuccessful result", path)
+	}
+	return nil
+}
+
+// PostHighlightWithContext records a new Highlight. The request is bound to
+// ctx, so callers can time it out or cancel it.
+func (r *RescueTime) PostHighlightWithContext(ctx context.Context, highlight *PostHighlightRequest) error {
+	return r.doPostAction(ctx, highlightsPostURL, highlight.encode())
+}
+
+// PostHighlight records a new Highlight.
+func (r *RescueTime) PostHighlight(highlight *PostHighlightRequest) error {
+	return r.PostHighlightWithContext(context.Background(), highlight)
+}
+
+// FocusTimeEvent is a single entry in a FocusTime started/ended feed.
+type FocusTimeEvent struct {
+	Date string `json:"date"`
+}
+
+// StartFocusTimeWithContext starts a FocusTime session for the user. The
+// request is bound to ctx, so callers can time it out or cancel it.
+func (r *RescueTime) StartFocusTimeWithContext(ctx context.Context) error {
+	return r.doPostAction(ctx, startFocusTimeURL, url.Values{})
+}
+
+// StartFocusTime starts a FocusTime session for the user.
+func (r *RescueTime) StartFocusTime() error {
+	return r.StartFocusTimeWithContext(context.Background())
+}
+
+// EndFocusTimeWithContext ends the user's active FocusTime session. The
+// request is bound to ctx, so callers can time it out or cancel it.
+func (r *RescueTime) EndFocusTimeWithContext(ctx context.Context) error {
+	return r.doPostAction(ctx, endFocusTimeURL, url.Values{})
+}
+
+// EndFocusTime ends the user's active FocusTime session.
+func (r *RescueTime) EndFocusTime() error {
+	return r.EndFocusTimeWithContext(context.Background())
+}
+
+// GetFocusTimeStartedFeedWithContext returns the feed of FocusTime start
+// events. The request is bound to ctx, so callers can time it out or cancel it.
+func (r *RescueTime) GetFocusTimeStartedFeedWithContext(ctx context.Context) ([]FocusTimeEvent, error) {
+	var events []FocusTimeEvent
+	if err := r.doJSON(ctx, focusTimeStartedFeedURL, nil, nil, &events); err != nil {
+		return events, err
+	}
+	return events, nil
+}
+
+// GetFocusTimeStartedFeed returns the feed of FocusTime start events.
+func (r *RescueTime) GetFocusTimeStartedFeed() ([]FocusTimeEvent, error) {
+	return r.GetFocusTimeStartedFeedWithContext(context.Background())
+}
+
+// GetFocusTimeEndedFeedWithContext returns the feed of FocusTime end events.
+// The request is bound to ctx, so callers can time it out or cancel it.
+func (r *RescueTime) GetFocusTimeEndedFeedWithContext(ctx context.Context) ([]FocusTimeEvent, error) {
+	var events []FocusTimeEvent
+	if err := r.doJSON(ctx, focusTimeEndedFeedURL, nil, nil, &events); err != nil {
+		return events, err
+	}
+	return events, nil
+}
+
+// GetFocusTimeEndedFeed returns the feed of FocusTime end events.
+func (r *RescueTime) GetFocusTimeEndedFeed() ([]FocusTimeEvent, error) {
+	return r.GetFocusTimeEndedFeedWithContext(context.Background())
+}
+
+// PostOfflineTimeRequest is the payload accepted by the Offline Time API,
+// used to record time spent away from tracked devices.
+type PostOfflineTimeRequest struct {
+	// StartTime is when the offline period began. Required.
+	StartTime time.Time
+	// DurationInSeconds is how long the offline period lasted. Required.
+	DurationInSeconds int
+	// Activity names the offline activity, e.g. "Meeting". Required.
+	Activity string
+	// Category buckets the activity, e.g. "Business Meetings".
+	Category string
+	// ProductivityScore rates the activity from -2 (very distracting) to
+	// 2 (very productive).
+	ProductivityScore int
+}
+
+func (p *PostOfflineTimeRequest) encode() url.Values {
+	values := url.Values{
+		"start_time":          {p.StartTime.Format("2006-01-02 15:04:05")},
+		"duration_in_seconds": {strconv.Itoa(p.DurationInSeconds)},
+		"activity_name":       {p.Activity},
+	}
+	if p.Category != "" {
+		values.Set("activity_details", p.Category)
 	}
-	var keys []DailySummary
-	err = json.Unmarshal(contents, &keys)
+	if p.ProductivityScore != 0 {
+		values.Set("productivity_score", strconv.Itoa(p.ProductivityScore))
+	}
+	return values
+}
+
+// PostOfflineTimeWithContext records a block of offline time for the user.
+// The request is bound to ctx, so callers can time it out or cancel it.
+func (r *RescueTime) PostOfflineTimeWithContext(ctx context.Context, offlineTime *PostOfflineTimeRequest) error {
+	return r.doPostAction(ctx, offlineTimePostURL, offlineTime.encode())
+}
+
+// PostOfflineTime records a block of offline time for the user.
+func (r *RescueTime) PostOfflineTime(offlineTime *PostOfflineTimeRequest) error {
+	return r.PostOfflineTimeWithContext(context.Background(), offlineTime)
+}
+
+// maxAnalyticDataSpan is the widest date range the Analytic Data API accepts
+// in a single request.
+const maxAnalyticDataSpan = 6 * 30 * 24 * time.Hour
+
+// IteratorOption configures an AnalyticDataIterator.
+type IteratorOption func(*AnalyticDataIterator)
+
+// WithChunkDuration overrides the sub-range length used to split a Period
+// across multiple Analytic Data API requests. It should not exceed the
+// API's six-month cap.
+func WithChunkDuration(d time.Duration) IteratorOption {
+	return func(it *AnalyticDataIterator) {
+		it.chunkDuration = d
+	}
+}
+
+// AnalyticDataIterator streams Analytic Data API rows over a Period too
+// wide for a single request, issuing one request per chunk as rows are
+// consumed. Use it like database/sql.Rows:
+//
+//	it, err := r.IterateAnalyticData(ctx, "", params, period)
+//	if err != nil {
+//		// handle err
+//	}
+//	defer it.Close()
+//	for it.Next() {
+//		row := it.Row()
+//	}
+//	if err := it.Err(); err != nil {
+//		// handle err
+//	}
+type AnalyticDataIterator struct {
+	r             *RescueTime
+	timezone      string
+	parameters    AnalyticDataQueryParameters
+	chunkDuration time.Duration
+	remaining     Period
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	rows    []Row
+	pos     int
+	current Row
+	done    bool
+	err     error
+}
+
+// IterateAnalyticData returns an iterator that transparently splits period
+// into Analytic-Data-API-sized chunks and streams the resulting rows. The
+// parameters' own RestrictBegin and RestrictEnd are overwritten per chunk.
+func (r *RescueTime) IterateAnalyticData(ctx context.Context, timezone string, parameters *AnalyticDataQueryParameters, period Period, opts ...IteratorOption) (*AnalyticDataIterator, error) {
+	if err := period.Validate(); err != nil {
+		return nil, err
+	}
+
+	iterCtx, cancel := context.WithCancel(ctx)
+	it := &AnalyticDataIterator{
+		r:             r,
+		timezone:      timezone,
+		parameters:    *parameters,
+		chunkDuration: maxAnalyticDataSpan,
+		remaining:     period,
+		ctx:           iterCtx,
+		cancel:        cancel,
+	}
+	for _, opt := range opts {
+		opt(it)
+	}
+	return it, nil
+}
+
+// Next advances the iterator to the next row, fetching additional chunks
+// from the API as needed. It returns false once iteration is complete or an
+// error has occurred; callers should check Err afterwards.
+func (it *AnalyticDataIterator) Next() bool {
+	for {
+		if it.err != nil || it.done {
+			return false
+		}
+		if it.pos < len(it.rows) {
+			it.current = it.rows[it.pos]
+			it.pos++
+			return true
+		}
+		if !it.fetchNextChunk() {
+			return false
+		}
+	}
+}
+
+func (it *AnalyticDataIterator) fetchNextChunk() bool {
+	if !it.remaining.End.After(it.remaining.Start) {
+		it.done = true
+		return false
+	}
+
+	chunkEnd := it.remaining.Start.Add(it.chunkDuration)
+	if chunkEnd.After(it.remaining.End) {
+		chunkEnd = it.remaining.End
+	}
+
+	params := it.parameters
+	params.RestrictBegin = it.remaining.Start
+	params.RestrictEnd = chunkEnd
+
+	data, err := it.r.GetAnalyticDataWithContext(it.ctx, it.timezone, &params)
 	if err != nil {
-		return summaries, err
+		it.err = err
+		return false
+	}
+
+	// The chunk just consumed (it.rows) may share its latest date with rows
+	// at the head of the new chunk, since RestrictEnd of one chunk equals
+	// RestrictBegin of the next. Drop only those genuinely repeated rows at
+	// the seam; rows that merely share a timestamp within a single chunk
+	// (e.g. one row per activity per interval) are not touched.
+	boundary := boundaryRows(it.rows)
+	rows := data.Rows
+	for len(rows) > 0 && len(boundary) > 0 {
+		idx := indexOfRow(boundary, rows[0])
+		if idx == -1 {
+			break
+		}
+		boundary = append(boundary[:idx], boundary[idx+1:]...)
+		rows = rows[1:]
+	}
+
+	it.rows = rows
+	it.pos = 0
+	it.remaining.Start = chunkEnd
+
+	if len(it.rows) == 0 {
+		return it.fetchNextChunk()
+	}
+	return true
+}
+
+// boundaryRows returns the trailing run of rows sharing the chunk's latest
+// Date: the rows that may reappear at the head of the next chunk.
+func boundaryRows(rows []Row) []Row {
+	if len(rows) == 0 {
+		return nil
+	}
+	maxDate := rows[len(rows)-1].Date
+	var boundary []Row
+	for i := len(rows) - 1; i >= 0 && rows[i].Date.Equal(maxDate); i-- {
+		boundary = append(boundary, rows[i])
+	}
+	return boundary
+}
+
+func indexOfRow(rows []Row, row Row) int {
+	for i, r := range rows {
+		if rowsEqual(r, row) {
+			return i
+		}
 	}
-	return keys, nil
+	return -1
+}
+
+func rowsEqual(a, b Row) bool {
+	return a.Date.Equal(b.Date) &&
+		a.Rank == b.Rank &&
+		a.TimeSpentSeconds == b.TimeSpentSeconds &&
+		a.NumberOfPeople == b.NumberOfPeople &&
+		a.Person == b.Person &&
+		a.Activity == b.Activity &&
+		a.Category == b.Category &&
+		a.Productivity == b.Productivity
+}
+
+// Row returns the row most recently produced by Next.
+func (it *AnalyticDataIterator) Row() Row {
+	return it.current
+}
+
+// Err returns the first error encountered during iteration, if any.
+func (it *AnalyticDataIterator) Err() error {
+	return it.err
+}
+
+// Close cancels the iterator's underlying context, aborting any in-flight
+// or future request. It is safe to call Close more than once.
+func (it *AnalyticDataIterator) Close() error {
+	it.cancel()
+	return nil
 }
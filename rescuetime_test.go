@@ -1,23 +1,27 @@
 package rescuetime
 
 import (
-	"fmt"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"reflect"
 	"testing"
+	"time"
 )
 
 var RescueTimeAPIKey = os.Getenv("RESCUE_TIME_KEY")
 
-func TestMain(m *testing.M) {
-	if RescueTimeAPIKey == "" {
-		fmt.Println("No API key provided in the RESCUE_TIME_KEY environment variable!")
-		os.Exit(1)
-	} else {
-		os.Exit(m.Run())
-	}
-}
+// TestDailySummary and TestGetData exercise the live API and are skipped
+// without a key; the rest of this file covers pure logic or runs against
+// local httptest servers and does not require one.
 
 func TestDailySummary(t *testing.T) {
+	if RescueTimeAPIKey == "" {
+		t.Skip("No API key provided in the RESCUE_TIME_KEY environment variable")
+	}
 	var rescue RescueTime
 	rescue.APIKey = RescueTimeAPIKey
 	response, err := rescue.GetDailySummary()
@@ -28,6 +32,9 @@ func TestDailySummary(t *testing.T) {
 }
 
 func TestGetData(t *testing.T) {
+	if RescueTimeAPIKey == "" {
+		t.Skip("No API key provided in the RESCUE_TIME_KEY environment variable")
+	}
 	var rescue RescueTime
 	rescue.APIKey = RescueTimeAPIKey
 	response, err := rescue.GetAnalyticData("", &AnalyticDataQueryParameters{})
@@ -36,3 +43,495 @@ func TestGetData(t *testing.T) {
 	}
 	t.Log(response)
 }
+
+func TestPeriodValidate(t *testing.T) {
+	jan1 := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	jan2 := jan1.AddDate(0, 0, 1)
+
+	cases := []struct {
+		name    string
+		period  Period
+		wantErr bool
+	}{
+		{"valid", Period{Start: jan1, End: jan2}, false},
+		{"zero start", Period{End: jan2}, true},
+		{"zero end", Period{Start: jan1}, true},
+		{"zero both", Period{}, true},
+		{"end equals start", Period{Start: jan1, End: jan1}, true},
+		{"end before start", Period{Start: jan2, End: jan1}, true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.period.Validate()
+			if tc.wantErr != (err != nil) {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestAnalyticDataQueryParametersEncode(t *testing.T) {
+	jan1 := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("only set fields are emitted", func(t *testing.T) {
+		params := &AnalyticDataQueryParameters{RestrictThing: "github.com"}
+		values, err := params.encode()
+		if err != nil {
+			t.Fatalf("encode() error = %v", err)
+		}
+		if got, want := values.Get("restrict_thing"), "github.com"; got != want {
+			t.Errorf("restrict_thing = %q, want %q", got, want)
+		}
+		for _, key := range []string{"perspective", "resolution_time", "restrict_group", "restrict_begin", "restrict_end", "restrict_kind", "restrict_thingy"} {
+			if values.Has(key) {
+				t.Errorf("unset field %q was encoded", key)
+			}
+		}
+	})
+
+	t.Run("dates are formatted YYYY-MM-DD", func(t *testing.T) {
+		params := &AnalyticDataQueryParameters{RestrictBegin: jan1, RestrictEnd: jan1.AddDate(0, 0, 1)}
+		values, err := params.encode()
+		if err != nil {
+			t.Fatalf("encode() error = %v", err)
+		}
+		if got, want := values.Get("restrict_begin"), "2026-01-01"; got != want {
+			t.Errorf("restrict_begin = %q, want %q", got, want)
+		}
+		if got, want := values.Get("restrict_end"), "2026-01-02"; got != want {
+			t.Errorf("restrict_end = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("valid enums are encoded", func(t *testing.T) {
+		params := &AnalyticDataQueryParameters{
+			Perspective:    PerspectiveInterval,
+			ResolutionTime: ResolutionDay,
+			RestrictKind:   KindActivity,
+		}
+		values, err := params.encode()
+		if err != nil {
+			t.Fatalf("encode() error = %v", err)
+		}
+		if got, want := values.Get("perspective"), "interval"; got != want {
+			t.Errorf("perspective = %q, want %q", got, want)
+		}
+		if got, want := values.Get("resolution_time"), "day"; got != want {
+			t.Errorf("resolution_time = %q, want %q", got, want)
+		}
+		if got, want := values.Get("restrict_kind"), "activity"; got != want {
+			t.Errorf("restrict_kind = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("invalid perspective is rejected", func(t *testing.T) {
+		params := &AnalyticDataQueryParameters{Perspective: Perspective("bogus")}
+		if _, err := params.encode(); err == nil {
+			t.Fatal("encode() error = nil, want error for invalid perspective")
+		}
+	})
+
+	t.Run("invalid resolution time is rejected", func(t *testing.T) {
+		params := &AnalyticDataQueryParameters{ResolutionTime: ResolutionTime("bogus")}
+		if _, err := params.encode(); err == nil {
+			t.Fatal("encode() error = nil, want error for invalid resolution time")
+		}
+	})
+
+	t.Run("invalid restrict kind is rejected", func(t *testing.T) {
+		params := &AnalyticDataQueryParameters{RestrictKind: RestrictKind("bogus")}
+		if _, err := params.encode(); err == nil {
+			t.Fatal("encode() error = nil, want error for invalid restrict kind")
+		}
+	})
+}
+
+// analyticDataFixture encodes an Analytic Data API response body from a
+// row_headers/rows pair, for feeding to a fake server in iterator tests.
+func analyticDataFixture(headers []string, rows [][]interface{}) []byte {
+	body, err := json.Marshal(map[string]interface{}{
+		"notes":       "",
+		"row_headers": headers,
+		"rows":        rows,
+	})
+	if err != nil {
+		panic(err)
+	}
+	return body
+}
+
+func TestAnalyticDataIterator(t *testing.T) {
+	headers := []string{"Date", "Time Spent (seconds)", "Number of People", "Activity", "Category", "Productivity"}
+
+	// Chunk 1 covers 2026-01-01 through 2026-01-03 and chunk 2 covers
+	// 2026-01-03 through 2026-01-05; both requests include the 2026-01-03
+	// boundary date, so the API returns activities C and D in both
+	// responses. Activities A and B share a date within chunk 1 and must
+	// both survive, since a shared timestamp alone is not a duplicate.
+	chunk1 := analyticDataFixture(headers, [][]interface{}{
+		{"2026-01-01T00:00:00", 60, 1, "A", "Work", 1},
+		{"2026-01-01T00:00:00", 30, 1, "B", "Work", 1},
+		{"2026-01-03T00:00:00", 10, 1, "C", "Work", 1},
+		{"2026-01-03T00:00:00", 20, 1, "D", "Work", 1},
+	})
+	chunk2 := analyticDataFixture(headers, [][]interface{}{
+		{"2026-01-03T00:00:00", 10, 1, "C", "Work", 1},
+		{"2026-01-03T00:00:00", 20, 1, "D", "Work", 1},
+		{"2026-01-04T00:00:00", 40, 1, "E", "Work", 1},
+		{"2026-01-05T00:00:00", 50, 1, "F", "Work", 1},
+	})
+
+	var requestedBegins []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		begin := req.URL.Query().Get("restrict_begin")
+		requestedBegins = append(requestedBegins, begin)
+		switch begin {
+		case "2026-01-01":
+			w.Write(chunk1)
+		case "2026-01-03":
+			w.Write(chunk2)
+		default:
+			t.Errorf("unexpected restrict_begin %q", begin)
+		}
+	}))
+	defer server.Close()
+
+	r := NewClient("test-key", WithBaseURL(server.URL))
+	period := Period{
+		Start: time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2026, time.January, 5, 0, 0, 0, 0, time.UTC),
+	}
+
+	it, err := r.IterateAnalyticData(context.Background(), "", &AnalyticDataQueryParameters{}, period, WithChunkDuration(48*time.Hour))
+	if err != nil {
+		t.Fatalf("IterateAnalyticData() error = %v", err)
+	}
+	defer it.Close()
+
+	var got []string
+	for it.Next() {
+		got = append(got, it.Row().Activity)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("iteration error = %v", err)
+	}
+
+	want := []string{"A", "B", "C", "D", "E", "F"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("activities = %v, want %v", got, want)
+	}
+	if want := []string{"2026-01-01", "2026-01-03"}; !reflect.DeepEqual(requestedBegins, want) {
+		t.Errorf("requested restrict_begin values = %v, want %v", requestedBegins, want)
+	}
+}
+
+func TestAnalyticDataIteratorRejectsInvalidPeriod(t *testing.T) {
+	r := NewClient("test-key")
+	_, err := r.IterateAnalyticData(context.Background(), "", &AnalyticDataQueryParameters{}, Period{})
+	if err == nil {
+		t.Fatal("IterateAnalyticData() error = nil, want error for zero-valued period")
+	}
+}
+
+func TestDoRetriesGetButNotPost(t *testing.T) {
+	fastPolicy := &ExponentialBackoff{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+
+	t.Run("GET is retried on 503", func(t *testing.T) {
+		var requests int
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			requests++
+			if requests == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			json.NewEncoder(w).Encode([]Alert{})
+		}))
+		defer server.Close()
+
+		r := NewClient("test-key", WithBaseURL(server.URL), WithRetryPolicy(fastPolicy))
+		if _, err := r.GetAlertsFeed(); err != nil {
+			t.Fatalf("GetAlertsFeed() error = %v", err)
+		}
+		if requests != 2 {
+			t.Errorf("requests = %d, want 2", requests)
+		}
+	})
+
+	t.Run("POST action is not retried on 503", func(t *testing.T) {
+		var requests int
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			requests++
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		r := NewClient("test-key", WithBaseURL(server.URL), WithRetryPolicy(fastPolicy))
+		if err := r.StartFocusTime(); err == nil {
+			t.Fatal("StartFocusTime() error = nil, want error for 503 response")
+		}
+		if requests != 1 {
+			t.Errorf("requests = %d, want 1 (no retry for a non-idempotent POST)", requests)
+		}
+	})
+}
+
+func TestExponentialBackoffRetry(t *testing.T) {
+	respWithStatus := func(status int, retryAfter string) *http.Response {
+		resp := &http.Response{StatusCode: status, Header: http.Header{}}
+		if retryAfter != "" {
+			resp.Header.Set("Retry-After", retryAfter)
+		}
+		return resp
+	}
+
+	cases := []struct {
+		name      string
+		policy    ExponentialBackoff
+		attempt   int
+		resp      *http.Response
+		err       error
+		wantRetry bool
+		wantDelay time.Duration
+	}{
+		{
+			name:      "retries a 429 with no Retry-After",
+			policy:    ExponentialBackoff{},
+			resp:      respWithStatus(http.StatusTooManyRequests, ""),
+			wantRetry: true,
+		},
+		{
+			name:      "honors Retry-After on a 503",
+			policy:    ExponentialBackoff{},
+			resp:      respWithStatus(http.StatusServiceUnavailable, "2"),
+			wantRetry: true,
+			wantDelay: 2 * time.Second,
+		},
+		{
+			name:      "retries transient 5xx statuses",
+			policy:    ExponentialBackoff{},
+			resp:      respWithStatus(http.StatusBadGateway, ""),
+			wantRetry: true,
+		},
+		{
+			name:      "does not retry a 400",
+			policy:    ExponentialBackoff{},
+			resp:      respWithStatus(http.StatusBadRequest, ""),
+			wantRetry: false,
+		},
+		{
+			name:      "does not retry a 200",
+			policy:    ExponentialBackoff{},
+			resp:      respWithStatus(http.StatusOK, ""),
+			wantRetry: false,
+		},
+		{
+			name:      "retries a timeout net.Error",
+			policy:    ExponentialBackoff{},
+			err:       context.DeadlineExceeded,
+			wantRetry: true,
+		},
+		{
+			name:      "does not retry a non-timeout error",
+			policy:    ExponentialBackoff{},
+			err:       errors.New("boom"),
+			wantRetry: false,
+		},
+		{
+			name:      "stops once MaxAttempts is reached",
+			policy:    ExponentialBackoff{MaxAttempts: 2},
+			attempt:   1,
+			resp:      respWithStatus(http.StatusTooManyRequests, ""),
+			wantRetry: false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			retry, delay := tc.policy.Retry(tc.attempt, tc.resp, tc.err)
+			if retry != tc.wantRetry {
+				t.Fatalf("Retry() retry = %v, want %v", retry, tc.wantRetry)
+			}
+			if tc.wantDelay != 0 && delay != tc.wantDelay {
+				t.Errorf("Retry() delay = %v, want %v", delay, tc.wantDelay)
+			}
+			if tc.wantRetry && tc.wantDelay == 0 && delay <= 0 {
+				t.Errorf("Retry() delay = %v, want > 0", delay)
+			}
+		})
+	}
+}
+
+func TestExponentialBackoffBackoff(t *testing.T) {
+	b := ExponentialBackoff{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	for attempt := 0; attempt < 10; attempt++ {
+		delay := b.backoff(attempt)
+		if delay <= 0 {
+			t.Fatalf("backoff(%d) = %v, want > 0", attempt, delay)
+		}
+		if delay > b.MaxDelay {
+			t.Fatalf("backoff(%d) = %v, want <= MaxDelay %v", attempt, delay, b.MaxDelay)
+		}
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	cases := []struct {
+		name      string
+		header    string
+		wantOK    bool
+		wantDelay time.Duration
+	}{
+		{"absent", "", false, 0},
+		{"seconds", "5", true, 5 * time.Second},
+		{"invalid", "not-a-time", false, 0},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := &http.Response{Header: http.Header{}}
+			if tc.header != "" {
+				resp.Header.Set("Retry-After", tc.header)
+			}
+			delay, ok := retryAfter(resp)
+			if ok != tc.wantOK {
+				t.Fatalf("retryAfter() ok = %v, want %v", ok, tc.wantOK)
+			}
+			if ok && delay != tc.wantDelay {
+				t.Errorf("retryAfter() delay = %v, want %v", delay, tc.wantDelay)
+			}
+		})
+	}
+
+	t.Run("http date", func(t *testing.T) {
+		future := time.Now().Add(10 * time.Second).UTC()
+		resp := &http.Response{Header: http.Header{}}
+		resp.Header.Set("Retry-After", future.Format(http.TimeFormat))
+		delay, ok := retryAfter(resp)
+		if !ok {
+			t.Fatal("retryAfter() ok = false, want true")
+		}
+		if delay <= 0 || delay > 11*time.Second {
+			t.Errorf("retryAfter() delay = %v, want ~10s", delay)
+		}
+	})
+}
+
+func TestPostHighlightRequestEncode(t *testing.T) {
+	jan1 := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("only set fields are emitted", func(t *testing.T) {
+		req := &PostHighlightRequest{Description: "Shipped the release"}
+		values := req.encode()
+		if got, want := values.Get("description"), "Shipped the release"; got != want {
+			t.Errorf("description = %q, want %q", got, want)
+		}
+		if values.Has("highlight_date") {
+			t.Error("unset Date was encoded as highlight_date")
+		}
+		if values.Has("source") {
+			t.Error("unset Source was encoded")
+		}
+	})
+
+	t.Run("date and source are encoded when set", func(t *testing.T) {
+		req := &PostHighlightRequest{Description: "Shipped the release", Date: jan1, Source: "my-integration"}
+		values := req.encode()
+		if got, want := values.Get("highlight_date"), "2026-01-01"; got != want {
+			t.Errorf("highlight_date = %q, want %q", got, want)
+		}
+		if got, want := values.Get("source"), "my-integration"; got != want {
+			t.Errorf("source = %q, want %q", got, want)
+		}
+	})
+}
+
+func TestPostOfflineTimeRequestEncode(t *testing.T) {
+	start := time.Date(2026, time.January, 1, 9, 30, 0, 0, time.UTC)
+
+	t.Run("only set fields are emitted", func(t *testing.T) {
+		req := &PostOfflineTimeRequest{StartTime: start, DurationInSeconds: 1800, Activity: "Meeting"}
+		values := req.encode()
+		if got, want := values.Get("start_time"), "2026-01-01 09:30:00"; got != want {
+			t.Errorf("start_time = %q, want %q", got, want)
+		}
+		if got, want := values.Get("duration_in_seconds"), "1800"; got != want {
+			t.Errorf("duration_in_seconds = %q, want %q", got, want)
+		}
+		if got, want := values.Get("activity_name"), "Meeting"; got != want {
+			t.Errorf("activity_name = %q, want %q", got, want)
+		}
+		if values.Has("activity_details") {
+			t.Error("unset Category was encoded as activity_details")
+		}
+		if values.Has("productivity_score") {
+			t.Error("unset ProductivityScore was encoded")
+		}
+	})
+
+	t.Run("category and productivity score are encoded when set", func(t *testing.T) {
+		req := &PostOfflineTimeRequest{
+			StartTime:         start,
+			DurationInSeconds: 1800,
+			Activity:          "Meeting",
+			Category:          "Business Meetings",
+			ProductivityScore: 2,
+		}
+		values := req.encode()
+		if got, want := values.Get("activity_details"), "Business Meetings"; got != want {
+			t.Errorf("activity_details = %q, want %q", got, want)
+		}
+		if got, want := values.Get("productivity_score"), "2"; got != want {
+			t.Errorf("productivity_score = %q, want %q", got, want)
+		}
+	})
+}
+
+func TestGetAlertsFeed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != alertsFeedURL {
+			t.Errorf("request path = %q, want %q", req.URL.Path, alertsFeedURL)
+		}
+		json.NewEncoder(w).Encode([]Alert{
+			{AlertID: 1, Name: "Too much Twitter", Description: "...", Type: "weekly"},
+		})
+	}))
+	defer server.Close()
+
+	r := NewClient("test-key", WithBaseURL(server.URL))
+	alerts, err := r.GetAlertsFeed()
+	if err != nil {
+		t.Fatalf("GetAlertsFeed() error = %v", err)
+	}
+	want := []Alert{{AlertID: 1, Name: "Too much Twitter", Description: "...", Type: "weekly"}}
+	if !reflect.DeepEqual(alerts, want) {
+		t.Errorf("GetAlertsFeed() = %v, want %v", alerts, want)
+	}
+}
+
+func TestPostHighlightSuccessfulFlag(t *testing.T) {
+	cases := []struct {
+		name       string
+		successful bool
+		wantErr    bool
+	}{
+		{"successful response", true, false},
+		{"unsuccessful response", false, true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				json.NewEncoder(w).Encode(postResponse{Successful: tc.successful})
+			}))
+			defer server.Close()
+
+			r := NewClient("test-key", WithBaseURL(server.URL))
+			err := r.PostHighlight(&PostHighlightRequest{Description: "Shipped the release"})
+			if tc.wantErr != (err != nil) {
+				t.Fatalf("PostHighlight() error = %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}